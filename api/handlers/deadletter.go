@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/grenarafa/billing-engine/worker"
+)
+
+// deadLetterTask is what GET /payments/dead-letter reports for an archived
+// (retries exhausted) payment:apply task.
+type deadLetterTask struct {
+	ID         string `json:"id"`
+	LastError  string `json:"last_error"`
+	MaxRetry   int    `json:"max_retry"`
+	Retried    int    `json:"retried"`
+	LastFailed string `json:"last_failed_at"`
+}
+
+// ListDeadLetter handles GET /payments/dead-letter, listing payment:apply
+// tasks that exhausted their retries.
+func (h *Handler) ListDeadLetter(c *gin.Context) {
+	archived, err := h.inspector.ListArchivedTasks(worker.QueuePayments)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to list dead letter queue"})
+		return
+	}
+
+	tasks := make([]deadLetterTask, 0, len(archived))
+	for _, t := range archived {
+		tasks = append(tasks, deadLetterTask{
+			ID:         t.ID,
+			LastError:  t.LastErr,
+			MaxRetry:   t.MaxRetry,
+			Retried:    t.Retried,
+			LastFailed: t.LastFailedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+
+	c.JSON(200, gin.H{"tasks": tasks})
+}
@@ -0,0 +1,132 @@
+// Package handlers adapts the Gin request/response cycle to the
+// service package.
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+
+	"github.com/grenarafa/billing-engine/bus"
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/service"
+	"github.com/grenarafa/billing-engine/storage"
+)
+
+// idempotencyKeyHeader is the header payment requests must set so retries
+// don't double-pay.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// Handler holds the dependencies shared by the loan HTTP handlers.
+type Handler struct {
+	svc       *service.LoanService
+	inspector *asynq.Inspector
+	bus       *bus.Bus
+}
+
+// New builds a Handler backed by svc, inspector, and eventBus.
+func New(svc *service.LoanService, inspector *asynq.Inspector, eventBus *bus.Bus) *Handler {
+	return &Handler{svc: svc, inspector: inspector, bus: eventBus}
+}
+
+// CreateLoan handles POST /loans.
+func (h *Handler) CreateLoan(c *gin.Context) {
+	var loan storage.Loan
+	if err := c.ShouldBindJSON(&loan); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := h.svc.CreateLoan(c.Request.Context(), &loan); err != nil {
+		if errors.Is(err, service.ErrProductNotFound) {
+			c.JSON(404, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(500, gin.H{"error": "Failed to create loan"})
+		return
+	}
+
+	c.JSON(201, loan)
+}
+
+// GetOutstanding handles GET /loans/:loan_id/outstanding.
+func (h *Handler) GetOutstanding(c *gin.Context) {
+	balance, err := h.svc.GetOutstanding(c.Request.Context(), c.Param("loan_id"))
+	if errors.Is(err, service.ErrLoanNotFound) {
+		c.JSON(404, gin.H{"error": "Loan not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load balance"})
+		return
+	}
+
+	c.JSON(200, gin.H{"remaining_balance": balance})
+}
+
+// MakePayment handles POST /loans/:loan_id/payments. It enqueues the
+// payment to be applied asynchronously and returns the task id to poll via
+// GET /payments/:task_id.
+func (h *Handler) MakePayment(c *gin.Context) {
+	idempotencyKey := c.GetHeader(idempotencyKeyHeader)
+	if idempotencyKey == "" {
+		c.JSON(400, gin.H{"error": "Idempotency-Key header is required"})
+		return
+	}
+
+	taskID, err := h.svc.EnqueuePayment(c.Request.Context(), c.Param("loan_id"), idempotencyKey)
+	if errors.Is(err, service.ErrLoanNotFound) {
+		c.JSON(404, gin.H{"error": "Loan not found"})
+		return
+	}
+	if errors.Is(err, service.ErrIdempotencyKeyConflict) {
+		c.JSON(409, gin.H{"error": "Idempotency-Key already used for a different loan"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to enqueue payment"})
+		return
+	}
+
+	c.JSON(202, gin.H{"task_id": taskID})
+}
+
+// GetPaymentStatus handles GET /payments/:task_id.
+func (h *Handler) GetPaymentStatus(c *gin.Context) {
+	task, err := h.svc.GetPaymentStatus(c.Request.Context(), c.Param("task_id"))
+	if errors.Is(err, service.ErrPaymentTaskNotFound) {
+		c.JSON(404, gin.H{"error": "Task not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load task"})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"task_id": task.ID,
+		"loan_id": task.LoanID,
+		"status":  task.Status,
+		"error":   task.Error,
+	})
+}
+
+// IsDelinquent handles GET /loans/:loan_id/delinquent, reading the status
+// the sweeper last computed rather than recomputing it here.
+func (h *Handler) IsDelinquent(c *gin.Context) {
+	status, err := h.svc.GetDelinquencyStatus(c.Request.Context(), c.Param("loan_id"))
+	if errors.Is(err, service.ErrDelinquencyStatusNotFound) {
+		c.JSON(404, gin.H{"error": "Delinquency status not available yet"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load delinquency status"})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"is_delinquent": status.Status == db.DelinquencyStatusDelinquent,
+		"reason":        status.Reason,
+	})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/service"
+)
+
+// CreateProduct handles POST /products.
+func (h *Handler) CreateProduct(c *gin.Context) {
+	var product db.LoanProduct
+	if err := c.ShouldBindJSON(&product); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := h.svc.CreateProduct(c.Request.Context(), &product); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to create product"})
+		return
+	}
+
+	c.JSON(201, product)
+}
+
+// GetProduct handles GET /products/:product_id.
+func (h *Handler) GetProduct(c *gin.Context) {
+	product, err := h.svc.GetProduct(c.Request.Context(), c.Param("product_id"))
+	if errors.Is(err, service.ErrProductNotFound) {
+		c.JSON(404, gin.H{"error": "Product not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load product"})
+		return
+	}
+
+	c.JSON(200, product)
+}
+
+// ListProducts handles GET /products.
+func (h *Handler) ListProducts(c *gin.Context) {
+	products, err := h.svc.ListProducts(c.Request.Context())
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to list products"})
+		return
+	}
+
+	c.JSON(200, gin.H{"products": products})
+}
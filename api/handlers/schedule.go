@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/grenarafa/billing-engine/service"
+	"github.com/grenarafa/billing-engine/storage"
+)
+
+// loadOwnedSchedule loads loan_id's loan and full repayment schedule,
+// writing an error response and returning ok=false if the caller isn't
+// logged in, the loan doesn't exist, or the loan doesn't belong to the
+// session's borrower.
+func (h *Handler) loadOwnedSchedule(c *gin.Context) (schedule []storage.Repayment, ok bool) {
+	borrowerID, loggedIn := sessions.Default(c).Get(borrowerSessionKey).(uint)
+	if !loggedIn {
+		c.JSON(401, gin.H{"error": "Login required"})
+		return nil, false
+	}
+
+	loanID := c.Param("loan_id")
+	loan, err := h.svc.GetLoan(c.Request.Context(), loanID)
+	if errors.Is(err, service.ErrLoanNotFound) {
+		c.JSON(404, gin.H{"error": "Loan not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load loan"})
+		return nil, false
+	}
+	if loan.BorrowerID != borrowerID {
+		c.JSON(403, gin.H{"error": "Not your loan"})
+		return nil, false
+	}
+
+	schedule, err = h.svc.GetSchedule(c.Request.Context(), loanID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load schedule"})
+		return nil, false
+	}
+
+	return schedule, true
+}
+
+// scheduleRow formats a repayment as the row both GetScheduleCSV and
+// GetSchedulePDF render: week, due date, amount, principal, interest,
+// paid, paid at.
+func scheduleRow(r storage.Repayment) []string {
+	paidAt := ""
+	if r.PaidAt != nil {
+		paidAt = r.PaidAt.Format(time.RFC3339)
+	}
+	return []string{
+		strconv.Itoa(r.WeekNo),
+		r.DueDate.Format(time.RFC3339),
+		fmt.Sprintf("%.2f", r.PrincipalDue+r.InterestDue),
+		fmt.Sprintf("%.2f", r.PrincipalDue),
+		fmt.Sprintf("%.2f", r.InterestDue),
+		strconv.FormatBool(r.Paid),
+		paidAt,
+	}
+}
+
+// GetScheduleCSV handles GET /loans/:loan_id/schedule.csv.
+func (h *Handler) GetScheduleCSV(c *gin.Context) {
+	schedule, ok := h.loadOwnedSchedule(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="schedule.csv"`)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"week", "due_date", "amount", "principal", "interest", "paid", "paid_at"})
+	for _, r := range schedule {
+		w.Write(scheduleRow(r))
+	}
+	w.Flush()
+}
+
+// scheduleColumns are the PDF table's headers, matching scheduleRow's
+// order.
+var scheduleColumns = []string{"Week", "Due Date", "Amount", "Principal", "Interest", "Paid", "Paid At"}
+
+// GetSchedulePDF handles GET /loans/:loan_id/schedule.pdf.
+func (h *Handler) GetSchedulePDF(c *gin.Context) {
+	schedule, ok := h.loadOwnedSchedule(c)
+	if !ok {
+		return
+	}
+
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 10, "Repayment Schedule", "", 1, "", false, 0, "")
+
+	const colWidth = 37.0
+	pdf.SetFont("Arial", "B", 10)
+	for _, header := range scheduleColumns {
+		pdf.CellFormat(colWidth, 8, header, "1", 0, "", false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Arial", "", 10)
+	for _, r := range schedule {
+		for _, cell := range scheduleRow(r) {
+			pdf.CellFormat(colWidth, 8, cell, "1", 0, "", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", `attachment; filename="schedule.pdf"`)
+	if err := pdf.Output(c.Writer); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to render schedule"})
+	}
+}
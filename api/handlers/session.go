@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+// borrowerSessionKey is the session key Login stores the authenticated
+// borrower's ID under.
+const borrowerSessionKey = "borrower_id"
+
+// Login handles POST /sessions. It checks the given secret against the
+// borrower's seeded BorrowerCredential row and, once that succeeds, stores
+// borrower_id in the session cookie; GetScheduleCSV and GetSchedulePDF
+// trust that session value to gate a borrower's own schedule downloads.
+func (h *Handler) Login(c *gin.Context) {
+	var body struct {
+		BorrowerID uint   `json:"borrower_id" binding:"required"`
+		Secret     string `json:"secret" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := h.svc.AuthenticateBorrower(c.Request.Context(), body.BorrowerID, body.Secret); err != nil {
+		c.JSON(401, gin.H{"error": "Invalid credentials"})
+		return
+	}
+
+	session := sessions.Default(c)
+	session.Set(borrowerSessionKey, body.BorrowerID)
+	if err := session.Save(); err != nil {
+		c.JSON(500, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(200, gin.H{"status": "ok"})
+}
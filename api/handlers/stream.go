@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/grenarafa/billing-engine/bus"
+)
+
+// upgrader allows any origin, matching the rest of the API's lack of
+// same-origin restrictions; it has no cookie-based auth to protect.
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// StreamLoan handles GET /loans/:loan_id/stream, upgrading to a WebSocket
+// and forwarding the loan's bus events as they're published.
+func (h *Handler) StreamLoan(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Println("stream: upgrade failed: ", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx := c.Request.Context()
+	sub := h.bus.Subscribe(ctx, bus.LoanChannel(c.Param("loan_id")))
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg.Payload)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// registerWebhookRequest is the body of POST /webhooks.
+type registerWebhookRequest struct {
+	URL string `json:"url"`
+}
+
+// RegisterWebhook handles POST /webhooks, subscribing a URL to every loan
+// lifecycle event with HMAC-signed deliveries.
+func (h *Handler) RegisterWebhook(c *gin.Context) {
+	var req registerWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.URL == "" {
+		c.JSON(400, gin.H{"error": "url is required"})
+		return
+	}
+
+	sub, err := h.svc.RegisterWebhook(c.Request.Context(), req.URL)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to register webhook"})
+		return
+	}
+
+	c.JSON(201, gin.H{"id": sub.ID, "url": sub.URL, "secret": sub.Secret})
+}
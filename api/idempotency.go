@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/grenarafa/billing-engine/db"
+)
+
+// idempotencyKeyHeader is the header a retried request sets so it replays
+// the original response instead of re-running the handler.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyTTL is how long a cached response is honored before the same
+// key can be reused for a new request.
+const idempotencyTTL = 24 * time.Hour
+
+// recordingWriter buffers a handler's response so it can be cached for
+// idempotent replay alongside being written to the real client.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Idempotent makes the wrapped handler safe to retry. A request without an
+// Idempotency-Key header runs normally. A request with one is hashed and
+// checked against taskDB: an unseen key runs the handler and caches its
+// response for idempotencyTTL unless it was a 5xx; a seen key with a matching
+// body replays the cached response; a seen key with a different body is
+// rejected with 409. A 5xx response is never cached, so a transient
+// failure (e.g. a DB hiccup) can still be retried with the same key once
+// the underlying issue clears.
+func Idempotent(taskDB *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(hash[:])
+
+		var existing db.IdempotencyRecord
+		if err := taskDB.Where("key = ? AND expires_at > ?", key, time.Now()).First(&existing).Error; err == nil {
+			if existing.RequestHash != requestHash {
+				c.JSON(409, gin.H{"error": "Idempotency-Key already used with a different request body"})
+				c.Abort()
+				return
+			}
+			c.Data(existing.StatusCode, "application/json", []byte(existing.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		rec := &recordingWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = rec
+
+		c.Next()
+
+		if c.IsAborted() || rec.status >= 500 {
+			return
+		}
+
+		taskDB.Save(&db.IdempotencyRecord{
+			Key:          key,
+			RequestHash:  requestHash,
+			StatusCode:   rec.status,
+			ResponseBody: rec.body.String(),
+			ExpiresAt:    time.Now().Add(idempotencyTTL),
+		})
+	}
+}
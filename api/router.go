@@ -0,0 +1,50 @@
+// Package api wires the HTTP handlers into a Gin router.
+package api
+
+import (
+	"log"
+
+	"github.com/gin-contrib/sessions"
+	redisstore "github.com/gin-contrib/sessions/redis"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/grenarafa/billing-engine/api/handlers"
+	"github.com/grenarafa/billing-engine/config"
+)
+
+// NewRouter builds the Gin engine for the billing API. taskDB backs the
+// Idempotency-Key cache; cfg provides the Redis address and secret the
+// borrower session store signs cookies with.
+func NewRouter(h *handlers.Handler, taskDB *gorm.DB, cfg config.Config) *gin.Engine {
+	r := gin.Default()
+
+	store, err := redisstore.NewStore(10, "tcp", cfg.RedisAddr, "", []byte(cfg.SessionSecret))
+	if err != nil {
+		log.Fatal("Failed to open session store: ", err)
+	}
+	r.Use(sessions.Sessions("billing_engine_session", store))
+
+	// MakePayment isn't wrapped in Idempotent: EnqueuePayment already
+	// dedupes retries via PaymentTask.idempotency_key, so a second generic
+	// cache here would just be redundant.
+	r.POST("/loans", Idempotent(taskDB), h.CreateLoan)
+	r.POST("/loans/:loan_id/payments", h.MakePayment)
+	r.GET("/loans/:loan_id/outstanding", h.GetOutstanding)
+	r.GET("/loans/:loan_id/delinquent", h.IsDelinquent)
+	r.GET("/loans/:loan_id/stream", h.StreamLoan)
+	r.GET("/loans/:loan_id/schedule.csv", h.GetScheduleCSV)
+	r.GET("/loans/:loan_id/schedule.pdf", h.GetSchedulePDF)
+	r.GET("/payments/dead-letter", h.ListDeadLetter)
+	r.GET("/payments/:task_id", h.GetPaymentStatus)
+
+	r.POST("/products", h.CreateProduct)
+	r.GET("/products", h.ListProducts)
+	r.GET("/products/:product_id", h.GetProduct)
+
+	r.POST("/webhooks", h.RegisterWebhook)
+
+	r.POST("/sessions", h.Login)
+
+	return r
+}
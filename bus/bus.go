@@ -0,0 +1,73 @@
+// Package bus publishes typed loan lifecycle events over Redis pub/sub so
+// other processes — the loan stream WebSocket gateway, the webhook
+// dispatcher — can react to them without polling the API.
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// EventType names a loan lifecycle event.
+type EventType string
+
+// Event types published on a loan's channel and on GlobalChannel.
+const (
+	LoanCreated        EventType = "loan.created"
+	PaymentReceived    EventType = "payment.received"
+	BalanceUpdated     EventType = "balance.updated"
+	DelinquencyChanged EventType = "delinquency.changed"
+	LoanPaidOff        EventType = "loan.paid_off"
+)
+
+// GlobalChannel carries every event regardless of loan, for subscribers
+// (like the webhook dispatcher) that fan out to many loans at once.
+const GlobalChannel = "loan_events"
+
+// Message is the envelope published for every event.
+type Message struct {
+	LoanID    string      `json:"loan_id"`
+	Type      EventType   `json:"type"`
+	Data      interface{} `json:"data"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Bus publishes and subscribes to loan lifecycle events over Redis pub/sub.
+type Bus struct {
+	redis *redis.Client
+}
+
+// New builds a Bus backed by redisClient.
+func New(redisClient *redis.Client) *Bus {
+	return &Bus{redis: redisClient}
+}
+
+// LoanChannel is the per-loan channel a dashboard subscribes to for just
+// that loan's events.
+func LoanChannel(loanID string) string {
+	return fmt.Sprintf("loan_update:%s", loanID)
+}
+
+// Publish sends an event for loanID to both its per-loan channel and
+// GlobalChannel.
+func (b *Bus) Publish(ctx context.Context, loanID string, eventType EventType, data interface{}) error {
+	msg := Message{LoanID: loanID, Type: eventType, Data: data, Timestamp: time.Now()}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := b.redis.Publish(ctx, LoanChannel(loanID), payload).Err(); err != nil {
+		return err
+	}
+	return b.redis.Publish(ctx, GlobalChannel, payload).Err()
+}
+
+// Subscribe opens a Redis pub/sub subscription to channels.
+func (b *Bus) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return b.redis.Subscribe(ctx, channels...)
+}
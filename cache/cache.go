@@ -0,0 +1,24 @@
+// Package cache wraps the Redis client used to cache per-loan balance and
+// delinquency lookups.
+package cache
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// New creates a Redis client pointed at addr.
+func New(addr string) *redis.Client {
+	return redis.NewClient(&redis.Options{Addr: addr})
+}
+
+// OutstandingKey is the cache key for a loan's remaining balance.
+func OutstandingKey(loanID string) string {
+	return fmt.Sprintf("loan:%s:outstanding", loanID)
+}
+
+// DelinquentKey is the cache key for a loan's delinquency status and reason.
+func DelinquentKey(loanID string) string {
+	return fmt.Sprintf("loan:%s:delinquent", loanID)
+}
@@ -0,0 +1,58 @@
+// Command billing-engine-worker consumes the payments queue and applies
+// payments enqueued by the API server.
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/grenarafa/billing-engine/bus"
+	"github.com/grenarafa/billing-engine/cache"
+	"github.com/grenarafa/billing-engine/config"
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/delinquency"
+	"github.com/grenarafa/billing-engine/storage"
+	"github.com/grenarafa/billing-engine/storage/cassandra"
+	"github.com/grenarafa/billing-engine/storage/postgres"
+	"github.com/grenarafa/billing-engine/worker"
+)
+
+// newRepository opens the LoanRepository selected by cfg.StorageDriver.
+func newRepository(cfg config.Config) (storage.LoanRepository, error) {
+	switch cfg.StorageDriver {
+	case config.StorageDriverCassandra:
+		return cassandra.New(cfg.CassandraHosts, cfg.CassandraKeyspace)
+	default:
+		return postgres.New(cfg.DSN)
+	}
+}
+
+func main() {
+	cfg := config.Load()
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		log.Fatal("Failed to open loan storage: ", err)
+	}
+
+	// The payment task idempotency table always lives in Postgres,
+	// independent of which LoanRepository backs the loan ledger.
+	taskDB, err := db.New(cfg.DSN)
+	if err != nil {
+		log.Fatal("Failed to connect to database: ", err)
+	}
+
+	redisClient := cache.New(cfg.RedisAddr)
+	eventBus := bus.New(redisClient)
+
+	processor := worker.NewProcessor(repo, taskDB, redisClient, eventBus)
+	srv := worker.NewServer(cfg.RedisAddr)
+
+	sweeper := delinquency.NewSweeper(repo, taskDB, redisClient, eventBus)
+	go sweeper.Run(context.Background())
+
+	log.Println("Worker consuming the payments queue")
+	if err := srv.Run(worker.NewMux(processor)); err != nil {
+		log.Fatal("Worker failed: ", err)
+	}
+}
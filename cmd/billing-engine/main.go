@@ -0,0 +1,69 @@
+// Command billing-engine runs the loan API server.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/grenarafa/billing-engine/api"
+	"github.com/grenarafa/billing-engine/api/handlers"
+	"github.com/grenarafa/billing-engine/bus"
+	"github.com/grenarafa/billing-engine/cache"
+	"github.com/grenarafa/billing-engine/config"
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/service"
+	"github.com/grenarafa/billing-engine/storage"
+	"github.com/grenarafa/billing-engine/storage/cassandra"
+	"github.com/grenarafa/billing-engine/storage/postgres"
+	"github.com/grenarafa/billing-engine/webhook"
+)
+
+// newRepository opens the LoanRepository selected by cfg.StorageDriver.
+func newRepository(cfg config.Config) (storage.LoanRepository, error) {
+	switch cfg.StorageDriver {
+	case config.StorageDriverCassandra:
+		return cassandra.New(cfg.CassandraHosts, cfg.CassandraKeyspace)
+	default:
+		return postgres.New(cfg.DSN)
+	}
+}
+
+func main() {
+	cfg := config.Load()
+
+	repo, err := newRepository(cfg)
+	if err != nil {
+		log.Fatal("Failed to open loan storage: ", err)
+	}
+
+	// The payment task idempotency table always lives in Postgres,
+	// independent of which LoanRepository backs the loan ledger.
+	taskDB, err := db.New(cfg.DSN)
+	if err != nil {
+		log.Fatal("Failed to connect to database: ", err)
+	}
+
+	redisClient := cache.New(cfg.RedisAddr)
+	eventBus := bus.New(redisClient)
+
+	redisOpt := asynq.RedisClientOpt{Addr: cfg.RedisAddr}
+	taskClient := asynq.NewClient(redisOpt)
+	defer taskClient.Close()
+	inspector := asynq.NewInspector(redisOpt)
+	defer inspector.Close()
+
+	dispatcher := webhook.NewDispatcher(taskDB)
+	go dispatcher.Run(context.Background(), eventBus)
+
+	svc := service.New(repo, taskDB, redisClient, taskClient, eventBus, cfg)
+	h := handlers.New(svc, inspector, eventBus)
+	r := api.NewRouter(h, taskDB, cfg)
+
+	fmt.Printf("Server running on port %s\n", cfg.Port)
+	if err := r.Run(":" + cfg.Port); err != nil {
+		log.Fatal("Server failed: ", err)
+	}
+}
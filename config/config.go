@@ -0,0 +1,53 @@
+// Package config loads runtime configuration from the environment so the
+// binary can be pointed at different databases, caches, and storage
+// backends without a rebuild.
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// Supported StorageDriver values.
+const (
+	StorageDriverPostgres  = "postgres"
+	StorageDriverCassandra = "cassandra"
+)
+
+// Config holds everything the service layer and its adapters need to boot.
+type Config struct {
+	DSN       string
+	RedisAddr string
+	Port      string
+
+	// StorageDriver selects the LoanRepository implementation: "postgres"
+	// (default) or "cassandra".
+	StorageDriver     string
+	CassandraHosts    []string
+	CassandraKeyspace string
+
+	// SessionSecret signs the borrower session cookie that gates the
+	// repayment schedule export endpoints.
+	SessionSecret string
+}
+
+// Load reads configuration from environment variables, falling back to the
+// values the service shipped with before it was made configurable.
+func Load() Config {
+	return Config{
+		DSN:               getEnv("DATABASE_DSN", "host=localhost user=postgres password=postgres dbname=loansystem port=5432 sslmode=disable"),
+		RedisAddr:         getEnv("REDIS_ADDR", "localhost:6379"),
+		Port:              getEnv("PORT", "8080"),
+		StorageDriver:     getEnv("STORAGE_DRIVER", StorageDriverPostgres),
+		CassandraHosts:    strings.Split(getEnv("CASSANDRA_HOSTS", "localhost"), ","),
+		CassandraKeyspace: getEnv("CASSANDRA_KEYSPACE", "billing_engine"),
+		SessionSecret:     getEnv("SESSION_SECRET", "dev-session-secret"),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
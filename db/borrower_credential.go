@@ -0,0 +1,12 @@
+package db
+
+import "time"
+
+// BorrowerCredential is the secret a borrower presents to POST /sessions
+// to prove they are who they claim to be. There's no self-service signup
+// yet, so rows are seeded directly against BorrowerID.
+type BorrowerCredential struct {
+	BorrowerID uint      `gorm:"primaryKey"`
+	Secret     string    `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
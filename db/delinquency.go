@@ -0,0 +1,26 @@
+package db
+
+import "time"
+
+// Delinquency status reasons written by the sweeper.
+const (
+	DelinquencyReasonCurrent           = "current"
+	DelinquencyReasonGrace             = "grace"
+	DelinquencyReasonConsecutiveMissed = "consecutive_missed"
+)
+
+// Delinquency statuses.
+const (
+	DelinquencyStatusCurrent    = "current"
+	DelinquencyStatusDelinquent = "delinquent"
+)
+
+// LoanDelinquencyStatus is the persisted result of the delinquency
+// sweeper's last pass over a loan, read by GET /loans/:loan_id/delinquent
+// instead of recomputing on every call.
+type LoanDelinquencyStatus struct {
+	LoanID    uint      `gorm:"primaryKey"`
+	Status    string    `gorm:"not null"`
+	Reason    string    `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,17 @@
+package db
+
+import "time"
+
+// IdempotencyRecord caches the response to a request made with a given
+// Idempotency-Key, so a retry bearing the same key returns it unmodified
+// instead of re-running the handler, and the same key reused with a
+// different request body is rejected as a conflict. Records are read and
+// written by api.Idempotent and expire after ExpiresAt.
+type IdempotencyRecord struct {
+	Key          string    `gorm:"primaryKey"`
+	RequestHash  string    `gorm:"not null"`
+	StatusCode   int       `gorm:"not null"`
+	ResponseBody string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
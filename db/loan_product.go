@@ -0,0 +1,24 @@
+package db
+
+import "time"
+
+// Loan product schedule types.
+const (
+	ScheduleFlat         = "flat"
+	ScheduleAmortized    = "amortized"
+	ScheduleInterestOnly = "interest_only"
+)
+
+// LoanProduct is a reusable loan template: term, rate, and how its
+// repayment schedule is computed. Loans reference a product by ProductID
+// instead of hard-coding their own rate and term.
+type LoanProduct struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	Name             string    `gorm:"not null" json:"name"`
+	Rate             float64   `gorm:"not null" json:"rate"`
+	TermWeeks        int       `gorm:"not null" json:"term_weeks"`
+	ScheduleType     string    `gorm:"not null;default:flat" json:"schedule_type"`
+	GracePeriodWeeks int       `json:"grace_period_weeks"`
+	LateFeePct       float64   `json:"late_fee_pct"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
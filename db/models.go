@@ -0,0 +1,51 @@
+// Package db owns the GORM connection and the persisted loan/repayment
+// models.
+package db
+
+import (
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Loan is a single borrower's loan.
+type Loan struct {
+	ID               uint      `gorm:"primaryKey"`
+	BorrowerID       uint      `gorm:"not null"`
+	ProductID        uint      `gorm:"not null"`
+	Amount           float64   `gorm:"not null"`
+	InterestRate     float64   `gorm:"not null"`
+	WeeklyPayment    float64   `gorm:"not null"`
+	RemainingBalance float64   `gorm:"not null"`
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+}
+
+// Repayment is one scheduled installment of a Loan. PrincipalDue and
+// InterestDue are set at schedule creation from the loan's product and
+// don't assume a flat weekly split.
+type Repayment struct {
+	ID           uint      `gorm:"primaryKey"`
+	LoanID       uint      `gorm:"not null"`
+	WeekNo       int       `gorm:"not null"`
+	PrincipalDue float64   `gorm:"not null"`
+	InterestDue  float64   `gorm:"not null"`
+	DueDate      time.Time `gorm:"not null"`
+	Paid         bool      `gorm:"default:false"`
+	PaidAt       *time.Time
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// New opens a Postgres connection via dsn and migrates the loan schema.
+func New(dsn string) (*gorm.DB, error) {
+	gormDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gormDB.AutoMigrate(&Loan{}, &Repayment{}, &PaymentTask{}, &LoanProduct{}, &WebhookSubscription{}, &LoanDelinquencyStatus{}, &IdempotencyRecord{}, &BorrowerCredential{}); err != nil {
+		return nil, err
+	}
+
+	return gormDB, nil
+}
@@ -0,0 +1,33 @@
+package db
+
+import "time"
+
+// Payment task lifecycle states.
+const (
+	PaymentStatusQueued     = "queued"
+	PaymentStatusProcessing = "processing"
+	PaymentStatusCompleted  = "completed"
+	PaymentStatusFailed     = "failed"
+)
+
+// PaymentTask tracks an asynchronously processed payment:apply task so that
+// GET /payments/:task_id has something to poll and retries of the same
+// Idempotency-Key don't enqueue (or apply) the payment twice.
+type PaymentTask struct {
+	ID             string  `gorm:"primaryKey"`
+	LoanID         uint    `gorm:"not null"`
+	IdempotencyKey string  `gorm:"uniqueIndex;not null"`
+	Amount         float64 `gorm:"not null"`
+	Status         string  `gorm:"not null;default:queued"`
+	Error          string
+
+	// TargetWeekNo is the repayment WeekNo this task applies to, set the
+	// first time the task runs. A redelivered task reuses it to correct
+	// the same repayment instead of advancing to "next unpaid", which
+	// would now be a different row once the first run's MarkRepaymentPaid
+	// has taken effect.
+	TargetWeekNo *int
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
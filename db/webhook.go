@@ -0,0 +1,12 @@
+package db
+
+import "time"
+
+// WebhookSubscription is a subscriber URL registered to receive loan
+// lifecycle events, HMAC-signed with Secret.
+type WebhookSubscription struct {
+	ID        string    `gorm:"primaryKey"`
+	URL       string    `gorm:"not null"`
+	Secret    string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,137 @@
+// Package delinquency runs the periodic sweep that recomputes every active
+// loan's delinquency status, persists it, pre-warms the Redis cache
+// GET /loans/:loan_id/delinquent reads from, and publishes a
+// bus.DelinquencyChanged event whenever a loan's status flips.
+package delinquency
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+
+	"github.com/grenarafa/billing-engine/bus"
+	"github.com/grenarafa/billing-engine/cache"
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/storage"
+)
+
+// delinquencyCacheSep mirrors service.delinquencyCacheSep; kept as a
+// separate constant since the two packages don't share internals.
+const delinquencyCacheSep = "|"
+
+// schedule is the cron expression the sweeper runs on: the top of every
+// hour.
+const schedule = "0 * * * *"
+
+// Sweeper recomputes and persists delinquency status for every active loan.
+type Sweeper struct {
+	repo   storage.LoanRepository
+	taskDB *gorm.DB
+	cache  *redis.Client
+	bus    *bus.Bus
+}
+
+// NewSweeper builds a Sweeper backed by repo, taskDB, redisClient, and
+// eventBus.
+func NewSweeper(repo storage.LoanRepository, taskDB *gorm.DB, redisClient *redis.Client, eventBus *bus.Bus) *Sweeper {
+	return &Sweeper{repo: repo, taskDB: taskDB, cache: redisClient, bus: eventBus}
+}
+
+// Run starts the hourly cron schedule and blocks until ctx is canceled. It
+// sweeps once immediately so a freshly started worker doesn't wait an hour
+// for its first pass.
+func (s *Sweeper) Run(ctx context.Context) {
+	s.sweepOnce(ctx)
+
+	c := cron.New()
+	if _, err := c.AddFunc(schedule, func() { s.sweepOnce(ctx) }); err != nil {
+		log.Fatal("delinquency: failed to schedule sweep: ", err)
+	}
+	c.Start()
+	defer c.Stop()
+
+	<-ctx.Done()
+}
+
+func (s *Sweeper) sweepOnce(ctx context.Context) {
+	loans, err := s.repo.ListActiveLoans(ctx)
+	if err != nil {
+		log.Println("delinquency: failed to list active loans: ", err)
+		return
+	}
+
+	for _, loan := range loans {
+		if err := s.sweepLoan(ctx, loan); err != nil {
+			log.Printf("delinquency: failed to sweep loan %d: %v", loan.ID, err)
+		}
+	}
+}
+
+func (s *Sweeper) sweepLoan(ctx context.Context, loan storage.Loan) error {
+	var product db.LoanProduct
+	if err := s.taskDB.WithContext(ctx).First(&product, loan.ProductID).Error; err != nil {
+		return fmt.Errorf("load product %d: %w", loan.ProductID, err)
+	}
+
+	unpaid, err := s.repo.UnpaidRepayments(ctx, loan.ID)
+	if err != nil {
+		return fmt.Errorf("load unpaid repayments: %w", err)
+	}
+
+	status, reason := classify(time.Now(), loan, product, unpaid)
+
+	var previous db.LoanDelinquencyStatus
+	hadPrevious := s.taskDB.WithContext(ctx).Where("loan_id = ?", loan.ID).First(&previous).Error == nil
+
+	row := db.LoanDelinquencyStatus{LoanID: loan.ID, Status: status, Reason: reason}
+	if err := s.taskDB.WithContext(ctx).Save(&row).Error; err != nil {
+		return fmt.Errorf("persist delinquency status: %w", err)
+	}
+
+	loanID := fmt.Sprint(loan.ID)
+	s.cache.Set(ctx, cache.DelinquentKey(loanID), status+delinquencyCacheSep+reason, time.Hour)
+
+	if !hadPrevious || previous.Status != status {
+		s.bus.Publish(ctx, loanID, bus.DelinquencyChanged, row)
+	}
+	return nil
+}
+
+// classify applies the delinquency rule: a loan is delinquent when it has
+// two or more consecutive scheduled repayments whose due date has passed
+// and that remain unpaid. unpaid must be ordered by WeekNo ascending, which
+// UnpaidRepayments guarantees, so the repayments due soonest — and thus the
+// oldest missed payments, if any — come first.
+func classify(now time.Time, loan storage.Loan, product db.LoanProduct, unpaid []storage.Repayment) (status, reason string) {
+	if len(unpaid) == 0 {
+		return db.DelinquencyStatusCurrent, db.DelinquencyReasonCurrent
+	}
+
+	consecutiveMissed := 0
+	prevWeekNo := 0
+	for _, r := range unpaid {
+		if r.DueDate.After(now) {
+			break
+		}
+		if consecutiveMissed > 0 && r.WeekNo == prevWeekNo+1 {
+			consecutiveMissed++
+		} else {
+			consecutiveMissed = 1
+		}
+		if consecutiveMissed >= 2 {
+			return db.DelinquencyStatusDelinquent, db.DelinquencyReasonConsecutiveMissed
+		}
+		prevWeekNo = r.WeekNo
+	}
+
+	graceEnd := loan.CreatedAt.Add(time.Duration(product.GracePeriodWeeks) * 7 * 24 * time.Hour)
+	if now.Before(graceEnd) {
+		return db.DelinquencyStatusCurrent, db.DelinquencyReasonGrace
+	}
+	return db.DelinquencyStatusCurrent, db.DelinquencyReasonCurrent
+}
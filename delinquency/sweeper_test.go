@@ -0,0 +1,60 @@
+package delinquency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/storage"
+)
+
+func TestClassifyCurrentWhenFullyPaid(t *testing.T) {
+	status, reason := classify(time.Now(), storage.Loan{}, db.LoanProduct{}, nil)
+	if status != db.DelinquencyStatusCurrent || reason != db.DelinquencyReasonCurrent {
+		t.Errorf("classify(no unpaid repayments) = (%q, %q), want (%q, %q)",
+			status, reason, db.DelinquencyStatusCurrent, db.DelinquencyReasonCurrent)
+	}
+}
+
+func TestClassifyOneMissedPaymentIsNotDelinquent(t *testing.T) {
+	now := time.Now()
+	unpaid := []storage.Repayment{
+		{WeekNo: 1, DueDate: now.Add(-48 * time.Hour)},
+		{WeekNo: 2, DueDate: now.Add(48 * time.Hour)},
+	}
+
+	status, reason := classify(now, storage.Loan{CreatedAt: now.Add(-30 * 24 * time.Hour)}, db.LoanProduct{}, unpaid)
+	if status != db.DelinquencyStatusCurrent {
+		t.Errorf("classify(one missed payment) = (%q, %q), want status %q", status, reason, db.DelinquencyStatusCurrent)
+	}
+}
+
+func TestClassifyTwoConsecutiveMissedPaymentsIsDelinquent(t *testing.T) {
+	now := time.Now()
+	unpaid := []storage.Repayment{
+		{WeekNo: 1, DueDate: now.Add(-14 * 24 * time.Hour)},
+		{WeekNo: 2, DueDate: now.Add(-7 * 24 * time.Hour)},
+		{WeekNo: 3, DueDate: now.Add(7 * 24 * time.Hour)},
+	}
+
+	status, reason := classify(now, storage.Loan{CreatedAt: now.Add(-30 * 24 * time.Hour)}, db.LoanProduct{}, unpaid)
+	if status != db.DelinquencyStatusDelinquent || reason != db.DelinquencyReasonConsecutiveMissed {
+		t.Errorf("classify(two consecutive missed) = (%q, %q), want (%q, %q)",
+			status, reason, db.DelinquencyStatusDelinquent, db.DelinquencyReasonConsecutiveMissed)
+	}
+}
+
+func TestClassifyWithinGracePeriod(t *testing.T) {
+	now := time.Now()
+	loan := storage.Loan{CreatedAt: now.Add(-3 * 24 * time.Hour)}
+	product := db.LoanProduct{GracePeriodWeeks: 2}
+	unpaid := []storage.Repayment{
+		{WeekNo: 1, DueDate: now.Add(11 * 24 * time.Hour)},
+	}
+
+	status, reason := classify(now, loan, product, unpaid)
+	if status != db.DelinquencyStatusCurrent || reason != db.DelinquencyReasonGrace {
+		t.Errorf("classify(within grace period) = (%q, %q), want (%q, %q)",
+			status, reason, db.DelinquencyStatusCurrent, db.DelinquencyReasonGrace)
+	}
+}
@@ -0,0 +1,292 @@
+// Package service implements the loan business logic on top of the storage
+// and cache packages, independent of the HTTP layer so it can be exercised
+// directly in tests.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+
+	"github.com/grenarafa/billing-engine/bus"
+	"github.com/grenarafa/billing-engine/cache"
+	"github.com/grenarafa/billing-engine/config"
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/storage"
+	"github.com/grenarafa/billing-engine/worker"
+)
+
+// ErrLoanNotFound is returned when a loan ID doesn't match any loan.
+var ErrLoanNotFound = errors.New("loan not found")
+
+// LoanService implements loan creation, repayment, and delinquency checks.
+// The loan ledger lives behind the pluggable storage.LoanRepository, while
+// payment task bookkeeping always lives in Postgres via taskDB.
+type LoanService struct {
+	repo   storage.LoanRepository
+	taskDB *gorm.DB
+	cache  *redis.Client
+	tasks  *asynq.Client
+	bus    *bus.Bus
+	cfg    config.Config
+}
+
+// New builds a LoanService backed by repo, taskDB, redisClient, taskClient,
+// and eventBus.
+func New(repo storage.LoanRepository, taskDB *gorm.DB, redisClient *redis.Client, taskClient *asynq.Client, eventBus *bus.Bus, cfg config.Config) *LoanService {
+	return &LoanService{repo: repo, taskDB: taskDB, cache: redisClient, tasks: taskClient, bus: eventBus, cfg: cfg}
+}
+
+// ErrProductNotFound is returned when a product ID doesn't match any loan
+// product.
+var ErrProductNotFound = errors.New("loan product not found")
+
+// ErrInvalidCredentials is returned when a borrower_id/secret pair
+// presented to Login doesn't match a seeded BorrowerCredential.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// AuthenticateBorrower checks secret against borrowerID's seeded
+// BorrowerCredential, returning ErrInvalidCredentials if there's no
+// credential row for borrowerID or its secret doesn't match.
+func (s *LoanService) AuthenticateBorrower(ctx context.Context, borrowerID uint, secret string) error {
+	var cred db.BorrowerCredential
+	if err := s.taskDB.WithContext(ctx).First(&cred, borrowerID).Error; err != nil {
+		return ErrInvalidCredentials
+	}
+	if cred.Secret != secret {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// CreateLoan persists a new loan and generates its repayment schedule from
+// loan.ProductID's rate, term, and schedule type.
+func (s *LoanService) CreateLoan(ctx context.Context, loan *storage.Loan) error {
+	var product db.LoanProduct
+	if err := s.taskDB.First(&product, loan.ProductID).Error; err != nil {
+		return ErrProductNotFound
+	}
+
+	weeklyPayment, schedule, err := buildSchedule(loan.Amount, product, time.Now())
+	if err != nil {
+		return err
+	}
+
+	loan.InterestRate = product.Rate
+	loan.WeeklyPayment = weeklyPayment
+	loan.RemainingBalance = totalPayable(schedule)
+
+	if err := s.repo.CreateLoan(ctx, loan, schedule); err != nil {
+		return err
+	}
+
+	s.bus.Publish(ctx, fmt.Sprint(loan.ID), bus.LoanCreated, loan)
+	return nil
+}
+
+// CreateProduct persists a new loan product.
+func (s *LoanService) CreateProduct(ctx context.Context, product *db.LoanProduct) error {
+	return s.taskDB.WithContext(ctx).Create(product).Error
+}
+
+// GetProduct returns the loan product at productID, or ErrProductNotFound.
+func (s *LoanService) GetProduct(ctx context.Context, productID string) (*db.LoanProduct, error) {
+	var product db.LoanProduct
+	if err := s.taskDB.WithContext(ctx).First(&product, productID).Error; err != nil {
+		return nil, ErrProductNotFound
+	}
+	return &product, nil
+}
+
+// ListProducts returns every loan product in the catalog.
+func (s *LoanService) ListProducts(ctx context.Context) ([]db.LoanProduct, error) {
+	var products []db.LoanProduct
+	if err := s.taskDB.WithContext(ctx).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// GetOutstanding returns a loan's remaining balance, preferring the Redis
+// cache over the repository.
+func (s *LoanService) GetOutstanding(ctx context.Context, loanID string) (float64, error) {
+	cacheKey := cache.OutstandingKey(loanID)
+
+	if cached, err := s.cache.Get(ctx, cacheKey).Result(); err == nil {
+		balance, err := strconv.ParseFloat(cached, 64)
+		if err == nil {
+			return balance, nil
+		}
+	}
+
+	loan, err := s.repo.GetLoan(ctx, loanID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, ErrLoanNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	s.cache.Set(ctx, cacheKey, fmt.Sprintf("%f", loan.RemainingBalance), 10*time.Minute)
+	return loan.RemainingBalance, nil
+}
+
+// GetLoan returns loanID's loan, or ErrLoanNotFound.
+func (s *LoanService) GetLoan(ctx context.Context, loanID string) (*storage.Loan, error) {
+	loan, err := s.repo.GetLoan(ctx, loanID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrLoanNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+// GetSchedule returns loanID's full repayment schedule, paid or not,
+// ordered by week_no ascending, for the schedule export endpoints.
+func (s *LoanService) GetSchedule(ctx context.Context, loanID string) ([]storage.Repayment, error) {
+	loan, err := s.GetLoan(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListRepayments(ctx, loan.ID)
+}
+
+// ErrIdempotencyKeyConflict is returned when idempotencyKey was already
+// used for a different loan than the one requested.
+var ErrIdempotencyKeyConflict = errors.New("idempotency key already used for a different loan")
+
+// EnqueuePayment enqueues a payment:apply task for loanID's next unpaid
+// repayment and returns the task ID the caller can poll for the result. A
+// retried request with the same idempotencyKey and loanID returns the
+// original task ID instead of enqueuing the payment twice; the same key
+// reused against a different loanID returns ErrIdempotencyKeyConflict.
+func (s *LoanService) EnqueuePayment(ctx context.Context, loanID, idempotencyKey string) (string, error) {
+	loan, err := s.repo.GetLoan(ctx, loanID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "", ErrLoanNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var existing db.PaymentTask
+	if err := s.taskDB.Where("idempotency_key = ?", idempotencyKey).First(&existing).Error; err == nil {
+		if existing.LoanID != loan.ID {
+			return "", ErrIdempotencyKeyConflict
+		}
+		return existing.ID, nil
+	}
+
+	// The amount due varies per period for amortized and interest-only
+	// schedules, so it comes from the next unpaid installment rather than
+	// loan.WeeklyPayment.
+	repayment, err := s.repo.NextUnpaidRepayment(ctx, loan.ID)
+	if err != nil {
+		return "", err
+	}
+	amount := repayment.PrincipalDue + repayment.InterestDue
+
+	taskID := uuid.NewString()
+	record := db.PaymentTask{
+		ID:             taskID,
+		LoanID:         loan.ID,
+		IdempotencyKey: idempotencyKey,
+		Amount:         amount,
+		Status:         db.PaymentStatusQueued,
+	}
+	if err := s.taskDB.Create(&record).Error; err != nil {
+		// Lost the race against a concurrent request with the same key.
+		if dupErr := s.taskDB.Where("idempotency_key = ?", idempotencyKey).First(&existing).Error; dupErr == nil {
+			if existing.LoanID != loan.ID {
+				return "", ErrIdempotencyKeyConflict
+			}
+			return existing.ID, nil
+		}
+		return "", err
+	}
+
+	task, err := worker.NewPaymentApplyTask(worker.PaymentApplyPayload{
+		LoanID:         loanID,
+		IdempotencyKey: idempotencyKey,
+		Amount:         amount,
+		Timestamp:      time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.tasks.EnqueueContext(ctx, task,
+		asynq.TaskID(taskID),
+		asynq.Queue(worker.QueuePayments),
+		asynq.MaxRetry(worker.MaxRetry),
+	); err != nil {
+		return "", err
+	}
+
+	return taskID, nil
+}
+
+// ErrPaymentTaskNotFound is returned when a task ID doesn't match any
+// enqueued payment.
+var ErrPaymentTaskNotFound = errors.New("payment task not found")
+
+// GetPaymentStatus returns the current state of a payment:apply task.
+func (s *LoanService) GetPaymentStatus(ctx context.Context, taskID string) (*db.PaymentTask, error) {
+	var task db.PaymentTask
+	if err := s.taskDB.First(&task, "id = ?", taskID).Error; err != nil {
+		return nil, ErrPaymentTaskNotFound
+	}
+	return &task, nil
+}
+
+// ErrDelinquencyStatusNotFound is returned when the sweeper hasn't written
+// a delinquency status for a loan yet.
+var ErrDelinquencyStatusNotFound = errors.New("delinquency status not found")
+
+// delinquencyCacheSep joins the cached status and reason fields, mirroring
+// how the sweeper pre-warms cache.DelinquentKey.
+const delinquencyCacheSep = "|"
+
+// GetDelinquencyStatus returns loanID's delinquency status as last computed
+// by the sweeper, preferring the Redis cache it pre-warms over the
+// persisted loan_delinquency_status row.
+func (s *LoanService) GetDelinquencyStatus(ctx context.Context, loanID string) (*db.LoanDelinquencyStatus, error) {
+	cacheKey := cache.DelinquentKey(loanID)
+
+	if cached, err := s.cache.Get(ctx, cacheKey).Result(); err == nil {
+		if status, reason, ok := strings.Cut(cached, delinquencyCacheSep); ok {
+			return &db.LoanDelinquencyStatus{Status: status, Reason: reason}, nil
+		}
+	}
+
+	var row db.LoanDelinquencyStatus
+	if err := s.taskDB.WithContext(ctx).Where("loan_id = ?", loanID).First(&row).Error; err != nil {
+		return nil, ErrDelinquencyStatusNotFound
+	}
+	return &row, nil
+}
+
+// RegisterWebhook subscribes url to receive HMAC-signed loan lifecycle
+// events, returning the generated secret so the caller can verify
+// deliveries.
+func (s *LoanService) RegisterWebhook(ctx context.Context, url string) (*db.WebhookSubscription, error) {
+	sub := db.WebhookSubscription{
+		ID:     uuid.NewString(),
+		URL:    url,
+		Secret: uuid.NewString(),
+	}
+	if err := s.taskDB.WithContext(ctx).Create(&sub).Error; err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/storage"
+)
+
+// weekDuration is how far apart two consecutive WeekNo installments fall.
+const weekDuration = 7 * 24 * time.Hour
+
+// buildSchedule computes the per-period payment and the full repayment
+// schedule for a loan of amount against product, per product.ScheduleType:
+//
+//   - flat: the legacy behavior, amount repaid plus a flat rate spread
+//     evenly over the term.
+//   - amortized: a constant payment each period via the standard annuity
+//     formula P = L * r / (1 - (1+r)^-n), with the principal/interest split
+//     recomputed period over period against the shrinking balance.
+//   - interest_only: each period pays interest on the full principal, with
+//     the principal due as a balloon payment in the final period.
+//
+// product.GracePeriodWeeks shifts every installment's WeekNo back so the
+// first one falls due after the grace period elapses. Each installment's
+// DueDate is createdAt plus its WeekNo in weeks.
+func buildSchedule(amount float64, product db.LoanProduct, createdAt time.Time) (weeklyPayment float64, schedule []storage.RepaymentSchedule, err error) {
+	n := product.TermWeeks
+	if n <= 0 {
+		return 0, nil, fmt.Errorf("product %d has a non-positive term_weeks", product.ID)
+	}
+	r := product.Rate
+
+	switch product.ScheduleType {
+	case db.ScheduleAmortized:
+		denom := 1 - math.Pow(1+r, -float64(n))
+		if denom == 0 {
+			return 0, nil, fmt.Errorf("product %d rate yields a degenerate amortization", product.ID)
+		}
+		payment := amount * r / denom
+
+		balance := amount
+		schedule = make([]storage.RepaymentSchedule, 0, n)
+		for week := 1; week <= n; week++ {
+			interestDue := balance * r
+			principalDue := payment - interestDue
+			balance -= principalDue
+			schedule = append(schedule, storage.RepaymentSchedule{
+				WeekNo:       product.GracePeriodWeeks + week,
+				PrincipalDue: principalDue,
+				InterestDue:  interestDue,
+			})
+		}
+		weeklyPayment = payment
+
+	case db.ScheduleInterestOnly:
+		interestDue := amount * r
+		schedule = make([]storage.RepaymentSchedule, 0, n)
+		for week := 1; week <= n; week++ {
+			principalDue := 0.0
+			if week == n {
+				principalDue = amount
+			}
+			schedule = append(schedule, storage.RepaymentSchedule{
+				WeekNo:       product.GracePeriodWeeks + week,
+				PrincipalDue: principalDue,
+				InterestDue:  interestDue,
+			})
+		}
+		weeklyPayment = interestDue
+
+	case db.ScheduleFlat, "":
+		totalPayable := amount * (1 + r)
+		payment := totalPayable / float64(n)
+		principalDue := amount / float64(n)
+		interestDue := payment - principalDue
+
+		schedule = make([]storage.RepaymentSchedule, 0, n)
+		for week := 1; week <= n; week++ {
+			schedule = append(schedule, storage.RepaymentSchedule{
+				WeekNo:       product.GracePeriodWeeks + week,
+				PrincipalDue: principalDue,
+				InterestDue:  interestDue,
+			})
+		}
+		weeklyPayment = payment
+
+	default:
+		return 0, nil, fmt.Errorf("product %d has unknown schedule_type %q", product.ID, product.ScheduleType)
+	}
+
+	for i := range schedule {
+		schedule[i].DueDate = createdAt.Add(time.Duration(schedule[i].WeekNo) * weekDuration)
+	}
+
+	return weeklyPayment, schedule, nil
+}
+
+// totalPayable sums the principal and interest due across schedule, i.e.
+// the loan's true remaining balance at origination. Schedule types other
+// than flat don't repay amount*(1+rate) in total, so this must be derived
+// from the generated schedule rather than recomputed from rate and term.
+func totalPayable(schedule []storage.RepaymentSchedule) float64 {
+	var total float64
+	for _, r := range schedule {
+		total += r.PrincipalDue + r.InterestDue
+	}
+	return total
+}
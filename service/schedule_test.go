@@ -0,0 +1,83 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grenarafa/billing-engine/db"
+)
+
+func TestBuildScheduleFlat(t *testing.T) {
+	product := db.LoanProduct{ID: 1, Rate: 0.1, TermWeeks: 2, ScheduleType: db.ScheduleFlat}
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	weeklyPayment, schedule, err := buildSchedule(1000, product, createdAt)
+	if err != nil {
+		t.Fatalf("buildSchedule returned error: %v", err)
+	}
+	if len(schedule) != 2 {
+		t.Fatalf("expected 2 installments, got %d", len(schedule))
+	}
+	if got, want := weeklyPayment, 550.0; math.Abs(got-want) > 0.01 {
+		t.Errorf("weeklyPayment = %v, want %v", got, want)
+	}
+	if got, want := totalPayable(schedule), 1100.0; math.Abs(got-want) > 0.01 {
+		t.Errorf("totalPayable = %v, want %v", got, want)
+	}
+	if !schedule[0].DueDate.Equal(createdAt.Add(7 * 24 * time.Hour)) {
+		t.Errorf("first due date = %v, want one week after createdAt", schedule[0].DueDate)
+	}
+}
+
+func TestBuildScheduleAmortized(t *testing.T) {
+	product := db.LoanProduct{ID: 2, Rate: 0.01, TermWeeks: 50, ScheduleType: db.ScheduleAmortized}
+
+	weeklyPayment, schedule, err := buildSchedule(1000, product, time.Now())
+	if err != nil {
+		t.Fatalf("buildSchedule returned error: %v", err)
+	}
+	if len(schedule) != 50 {
+		t.Fatalf("expected 50 installments, got %d", len(schedule))
+	}
+
+	// A constant-payment annuity pays the same amount each period.
+	for _, r := range schedule {
+		if got := r.PrincipalDue + r.InterestDue; math.Abs(got-weeklyPayment) > 0.01 {
+			t.Errorf("installment total = %v, want constant payment %v", got, weeklyPayment)
+		}
+	}
+
+	// The amortized total owed is well above the flat-rate total the old
+	// RemainingBalance formula assumed.
+	if total := totalPayable(schedule); total < 1200 {
+		t.Errorf("totalPayable = %v, want an amortized total well above amount*(1+rate)", total)
+	}
+}
+
+func TestBuildScheduleInterestOnly(t *testing.T) {
+	product := db.LoanProduct{ID: 3, Rate: 0.02, TermWeeks: 4, ScheduleType: db.ScheduleInterestOnly}
+
+	_, schedule, err := buildSchedule(1000, product, time.Now())
+	if err != nil {
+		t.Fatalf("buildSchedule returned error: %v", err)
+	}
+
+	for i, r := range schedule[:len(schedule)-1] {
+		if r.PrincipalDue != 0 {
+			t.Errorf("installment %d: principal = %v, want 0 before the balloon payment", i, r.PrincipalDue)
+		}
+	}
+	last := schedule[len(schedule)-1]
+	if last.PrincipalDue != 1000 {
+		t.Errorf("final installment principal = %v, want the full 1000 balloon payment", last.PrincipalDue)
+	}
+}
+
+func TestBuildScheduleNonPositiveTerm(t *testing.T) {
+	product := db.LoanProduct{ID: 4, Rate: 0.01, TermWeeks: 0, ScheduleType: db.ScheduleFlat}
+
+	if _, _, err := buildSchedule(1000, product, time.Now()); err == nil {
+		t.Fatal("expected an error for a non-positive term_weeks, got nil")
+	}
+}
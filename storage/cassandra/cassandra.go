@@ -0,0 +1,392 @@
+// Package cassandra implements storage.LoanRepository on top of Cassandra
+// for deployments that need horizontal write scaling on the repayment
+// ledger. Repayments are modeled as a wide row keyed by loan_id with
+// week_no as the clustering key:
+//
+//	CREATE TABLE loans (
+//	    id bigint PRIMARY KEY,
+//	    borrower_id bigint,
+//	    product_id bigint,
+//	    amount double,
+//	    interest_rate double,
+//	    weekly_payment double,
+//	    remaining_balance double,
+//	    created_at timestamp
+//	);
+//
+//	CREATE TABLE repayments (
+//	    loan_id bigint,
+//	    week_no int,
+//	    principal_due double,
+//	    interest_due double,
+//	    due_date timestamp,
+//	    paid boolean,
+//	    paid_at timestamp,
+//	    created_at timestamp,
+//	    PRIMARY KEY (loan_id, week_no)
+//	) WITH CLUSTERING ORDER BY (week_no ASC);
+package cassandra
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocql/gocql"
+
+	"github.com/grenarafa/billing-engine/storage"
+)
+
+// maxLoanIDAttempts bounds how many random candidate IDs CreateLoan tries
+// before giving up; with 63 bits of ID space a collision on the first
+// attempt is already vanishingly unlikely.
+const maxLoanIDAttempts = 5
+
+// Store is a storage.LoanRepository backed by Cassandra.
+type Store struct {
+	session *gocql.Session
+}
+
+// New connects to the Cassandra cluster at hosts/keyspace with
+// LOCAL_QUORUM consistency and ensures the schema above exists.
+func New(hosts []string, keyspace string) (*Store, error) {
+	cluster := gocql.NewCluster(hosts...)
+	cluster.Keyspace = keyspace
+	cluster.Consistency = gocql.LocalQuorum
+
+	session, err := cluster.CreateSession()
+	if err != nil {
+		return nil, err
+	}
+
+	store := &Store{session: session}
+	if err := store.migrate(); err != nil {
+		session.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *Store) migrate() error {
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS loans (
+			id bigint PRIMARY KEY,
+			borrower_id bigint,
+			product_id bigint,
+			amount double,
+			interest_rate double,
+			weekly_payment double,
+			remaining_balance double,
+			created_at timestamp
+		)`,
+		`CREATE TABLE IF NOT EXISTS repayments (
+			loan_id bigint,
+			week_no int,
+			principal_due double,
+			interest_due double,
+			due_date timestamp,
+			paid boolean,
+			paid_at timestamp,
+			created_at timestamp,
+			PRIMARY KEY (loan_id, week_no)
+		) WITH CLUSTERING ORDER BY (week_no ASC)`,
+	}
+	for _, stmt := range stmts {
+		if err := s.session.Query(stmt).Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithinTransaction runs fn unmodified: Cassandra has no cross-row
+// transactions, so every repository method that needs atomicity (marking a
+// repayment paid) uses a lightweight transaction (IF paid = false) instead.
+func (s *Store) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// randomLoanID returns a random positive 63-bit candidate loan ID.
+// Candidates are claimed with an IF NOT EXISTS lightweight transaction
+// rather than minted from a counter: Cassandra counters have no
+// compare-and-swap, so two concurrent increments can read the same
+// post-increment value and silently collide on one loan ID.
+func randomLoanID() (uint, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return uint(binary.BigEndian.Uint64(b[:]) &^ (1 << 63)), nil
+}
+
+// CreateLoan persists loan and its repayment schedule. loan.ID is claimed
+// by trying random candidates against an IF NOT EXISTS lightweight
+// transaction, up to maxLoanIDAttempts times, so concurrent callers can
+// never be handed the same ID; if none is accepted it returns an error.
+func (s *Store) CreateLoan(ctx context.Context, loan *storage.Loan, schedule []storage.RepaymentSchedule) error {
+	loan.CreatedAt = time.Now()
+
+	var claimed bool
+	for attempt := 0; attempt < maxLoanIDAttempts; attempt++ {
+		id, err := randomLoanID()
+		if err != nil {
+			return err
+		}
+
+		applied, err := s.session.Query(
+			`INSERT INTO loans (id, borrower_id, product_id, amount, interest_rate, weekly_payment, remaining_balance, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?) IF NOT EXISTS`,
+			id, loan.BorrowerID, loan.ProductID, loan.Amount, loan.InterestRate, loan.WeeklyPayment, loan.RemainingBalance, loan.CreatedAt,
+		).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+		if err != nil {
+			return err
+		}
+		if applied {
+			loan.ID = id
+			claimed = true
+			break
+		}
+	}
+	if !claimed {
+		return fmt.Errorf("cassandra: could not claim a loan ID after %d attempts", maxLoanIDAttempts)
+	}
+
+	for _, entry := range schedule {
+		if err := s.session.Query(
+			`INSERT INTO repayments (loan_id, week_no, principal_due, interest_due, due_date, paid, created_at) VALUES (?, ?, ?, ?, ?, false, ?)`,
+			loan.ID, entry.WeekNo, entry.PrincipalDue, entry.InterestDue, entry.DueDate, time.Now(),
+		).WithContext(ctx).Exec(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListActiveLoans returns every loan with a positive remaining balance.
+// Cassandra has no secondary index on remaining_balance by default, so this
+// scans the full loans table and filters client-side; fine for the hourly
+// sweeper's batch size, not for a hot path.
+func (s *Store) ListActiveLoans(ctx context.Context) ([]storage.Loan, error) {
+	iter := s.session.Query(
+		`SELECT id, borrower_id, product_id, amount, interest_rate, weekly_payment, remaining_balance, created_at FROM loans`,
+	).WithContext(ctx).Iter()
+
+	var loans []storage.Loan
+	var loan storage.Loan
+	for iter.Scan(
+		&loan.ID, &loan.BorrowerID, &loan.ProductID, &loan.Amount, &loan.InterestRate, &loan.WeeklyPayment, &loan.RemainingBalance, &loan.CreatedAt,
+	) {
+		if loan.RemainingBalance > 0 {
+			loans = append(loans, loan)
+		}
+	}
+	return loans, iter.Close()
+}
+
+// GetLoan returns the loan row, or storage.ErrNotFound.
+func (s *Store) GetLoan(ctx context.Context, loanID string) (*storage.Loan, error) {
+	var loan storage.Loan
+	err := s.session.Query(
+		`SELECT id, borrower_id, product_id, amount, interest_rate, weekly_payment, remaining_balance, created_at FROM loans WHERE id = ?`,
+		loanID,
+	).WithContext(ctx).Scan(
+		&loan.ID, &loan.BorrowerID, &loan.ProductID, &loan.Amount, &loan.InterestRate, &loan.WeeklyPayment, &loan.RemainingBalance, &loan.CreatedAt,
+	)
+	if err == gocql.ErrNotFound {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+// LockLoanForUpdate returns the loan row. Cassandra has no row locks;
+// MarkRepaymentPaid's lightweight transaction is what prevents a repayment
+// from being double-applied.
+func (s *Store) LockLoanForUpdate(ctx context.Context, loanID string) (*storage.Loan, error) {
+	return s.GetLoan(ctx, loanID)
+}
+
+// NextUnpaidRepayment returns the earliest unpaid repayment for loanID.
+func (s *Store) NextUnpaidRepayment(ctx context.Context, loanID uint) (*storage.Repayment, error) {
+	iter := s.session.Query(
+		`SELECT week_no, principal_due, interest_due, due_date, paid, paid_at, created_at FROM repayments WHERE loan_id = ? AND paid = false ALLOW FILTERING`,
+		loanID,
+	).WithContext(ctx).Iter()
+
+	var repayment storage.Repayment
+	repayment.LoanID = loanID
+	best := -1
+	for {
+		var weekNo int
+		var principalDue, interestDue float64
+		var dueDate time.Time
+		var paid bool
+		var paidAt, createdAt time.Time
+		if !iter.Scan(&weekNo, &principalDue, &interestDue, &dueDate, &paid, &paidAt, &createdAt) {
+			break
+		}
+		if best == -1 || weekNo < best {
+			best = weekNo
+			repayment.WeekNo = weekNo
+			repayment.PrincipalDue = principalDue
+			repayment.InterestDue = interestDue
+			repayment.DueDate = dueDate
+			repayment.Paid = paid
+			repayment.PaidAt = timeOrNil(paidAt)
+			repayment.CreatedAt = createdAt
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+	if best == -1 {
+		return nil, storage.ErrNoPendingRepayments
+	}
+	return &repayment, nil
+}
+
+// GetRepayment returns the repayment at (loanID, weekNo), or
+// storage.ErrNotFound. loan_id and week_no are the full primary key, so
+// this needs no ALLOW FILTERING.
+func (s *Store) GetRepayment(ctx context.Context, loanID uint, weekNo int) (*storage.Repayment, error) {
+	var principalDue, interestDue float64
+	var dueDate, paidAt, createdAt time.Time
+	var paid bool
+	err := s.session.Query(
+		`SELECT principal_due, interest_due, due_date, paid, paid_at, created_at FROM repayments WHERE loan_id = ? AND week_no = ?`,
+		loanID, weekNo,
+	).WithContext(ctx).Scan(&principalDue, &interestDue, &dueDate, &paid, &paidAt, &createdAt)
+	if err == gocql.ErrNotFound {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &storage.Repayment{
+		LoanID:       loanID,
+		WeekNo:       weekNo,
+		PrincipalDue: principalDue,
+		InterestDue:  interestDue,
+		DueDate:      dueDate,
+		Paid:         paid,
+		PaidAt:       timeOrNil(paidAt),
+		CreatedAt:    createdAt,
+	}, nil
+}
+
+// UnpaidRepayments returns loanID's unpaid repayments ordered by week_no
+// ascending.
+func (s *Store) UnpaidRepayments(ctx context.Context, loanID uint) ([]storage.Repayment, error) {
+	iter := s.session.Query(
+		`SELECT week_no, principal_due, interest_due, due_date, paid, paid_at, created_at FROM repayments WHERE loan_id = ? AND paid = false ALLOW FILTERING`,
+		loanID,
+	).WithContext(ctx).Iter()
+
+	var repayments []storage.Repayment
+	var weekNo int
+	var principalDue, interestDue float64
+	var dueDate, paidAt, createdAt time.Time
+	var paid bool
+	for iter.Scan(&weekNo, &principalDue, &interestDue, &dueDate, &paid, &paidAt, &createdAt) {
+		repayments = append(repayments, storage.Repayment{
+			LoanID:       loanID,
+			WeekNo:       weekNo,
+			PrincipalDue: principalDue,
+			InterestDue:  interestDue,
+			DueDate:      dueDate,
+			Paid:         paid,
+			PaidAt:       timeOrNil(paidAt),
+			CreatedAt:    createdAt,
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(repayments, func(i, j int) bool { return repayments[i].WeekNo < repayments[j].WeekNo })
+	return repayments, nil
+}
+
+// ListRepayments returns every repayment for loanID, paid or not, ordered
+// by week_no ascending. Unlike UnpaidRepayments, this needs no
+// ALLOW FILTERING: loan_id is the partition key, so Cassandra already
+// returns rows in clustering (week_no) order.
+func (s *Store) ListRepayments(ctx context.Context, loanID uint) ([]storage.Repayment, error) {
+	iter := s.session.Query(
+		`SELECT week_no, principal_due, interest_due, due_date, paid, paid_at, created_at FROM repayments WHERE loan_id = ?`,
+		loanID,
+	).WithContext(ctx).Iter()
+
+	var repayments []storage.Repayment
+	var weekNo int
+	var principalDue, interestDue float64
+	var dueDate, paidAt, createdAt time.Time
+	var paid bool
+	for iter.Scan(&weekNo, &principalDue, &interestDue, &dueDate, &paid, &paidAt, &createdAt) {
+		repayments = append(repayments, storage.Repayment{
+			LoanID:       loanID,
+			WeekNo:       weekNo,
+			PrincipalDue: principalDue,
+			InterestDue:  interestDue,
+			DueDate:      dueDate,
+			Paid:         paid,
+			PaidAt:       timeOrNil(paidAt),
+			CreatedAt:    createdAt,
+		})
+	}
+	return repayments, iter.Close()
+}
+
+// timeOrNil converts Cassandra's zero-value timestamp (an unset paid_at
+// column) to a nil *time.Time.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// MarkRepaymentPaid marks the repayment at (loanID, weekNo) paid using a
+// lightweight transaction so a concurrent retry can't apply it twice.
+func (s *Store) MarkRepaymentPaid(ctx context.Context, loanID uint, weekNo int) error {
+	applied, err := s.session.Query(
+		`UPDATE repayments SET paid = true, paid_at = ? WHERE loan_id = ? AND week_no = ? IF paid = false`,
+		time.Now(), loanID, weekNo,
+	).WithContext(ctx).MapScanCAS(map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return storage.ErrNoPendingRepayments
+	}
+	return nil
+}
+
+// CountUnpaidBefore counts loanID's unpaid repayments due before week.
+func (s *Store) CountUnpaidBefore(ctx context.Context, loanID uint, week int) (int64, error) {
+	iter := s.session.Query(
+		`SELECT week_no FROM repayments WHERE loan_id = ? AND week_no < ? AND paid = false ALLOW FILTERING`,
+		loanID, week,
+	).WithContext(ctx).Iter()
+
+	var count int64
+	var weekNo int
+	for iter.Scan(&weekNo) {
+		count++
+	}
+	return count, iter.Close()
+}
+
+// UpdateBalance sets loanID's remaining balance.
+func (s *Store) UpdateBalance(ctx context.Context, loanID uint, remainingBalance float64) error {
+	return s.session.Query(
+		`UPDATE loans SET remaining_balance = ? WHERE id = ?`,
+		remainingBalance, loanID,
+	).WithContext(ctx).Exec()
+}
@@ -0,0 +1,37 @@
+package cassandra
+
+import "testing"
+
+// TestRandomLoanIDFitsSignedBigint checks the property CreateLoan's retry
+// loop depends on: every candidate ID must fit in Cassandra's signed
+// bigint column, since the top bit is masked off rather than the value
+// being reduced mod 2^63.
+func TestRandomLoanIDFitsSignedBigint(t *testing.T) {
+	const maxInt63 = 1<<63 - 1
+
+	for i := 0; i < 1000; i++ {
+		id, err := randomLoanID()
+		if err != nil {
+			t.Fatalf("randomLoanID returned error: %v", err)
+		}
+		if uint64(id) > maxInt63 {
+			t.Fatalf("randomLoanID = %d, overflows a signed bigint", id)
+		}
+	}
+}
+
+// TestRandomLoanIDVaries is a smoke test that candidates aren't all the
+// same value, which would defeat the IF NOT EXISTS retry loop's purpose.
+func TestRandomLoanIDVaries(t *testing.T) {
+	seen := make(map[uint]bool)
+	for i := 0; i < 100; i++ {
+		id, err := randomLoanID()
+		if err != nil {
+			t.Fatalf("randomLoanID returned error: %v", err)
+		}
+		seen[id] = true
+	}
+	if len(seen) < 90 {
+		t.Errorf("got only %d distinct IDs out of 100 draws, want close to 100", len(seen))
+	}
+}
@@ -0,0 +1,216 @@
+// Package postgres implements storage.LoanRepository on top of GORM.
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/storage"
+)
+
+// Store is a storage.LoanRepository backed by Postgres via GORM.
+type Store struct {
+	db *gorm.DB
+}
+
+// New opens a Postgres connection via dsn and migrates the loan schema.
+func New(dsn string) (*Store, error) {
+	gormDB, err := db.New(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: gormDB}, nil
+}
+
+type txKey struct{}
+
+// WithinTransaction runs fn inside a GORM transaction.
+func (s *Store) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction stashed in ctx by WithinTransaction, or a
+// plain connection if there isn't one.
+func (s *Store) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return s.db.WithContext(ctx)
+}
+
+// CreateLoan persists loan and its repayment schedule in one transaction.
+func (s *Store) CreateLoan(ctx context.Context, loan *storage.Loan, schedule []storage.RepaymentSchedule) error {
+	row := db.Loan{
+		BorrowerID:       loan.BorrowerID,
+		ProductID:        loan.ProductID,
+		Amount:           loan.Amount,
+		InterestRate:     loan.InterestRate,
+		WeeklyPayment:    loan.WeeklyPayment,
+		RemainingBalance: loan.RemainingBalance,
+	}
+
+	return s.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.conn(ctx).Create(&row).Error; err != nil {
+			return err
+		}
+
+		for _, entry := range schedule {
+			repayment := db.Repayment{
+				LoanID:       row.ID,
+				WeekNo:       entry.WeekNo,
+				PrincipalDue: entry.PrincipalDue,
+				InterestDue:  entry.InterestDue,
+				DueDate:      entry.DueDate,
+				Paid:         false,
+			}
+			if err := s.conn(ctx).Create(&repayment).Error; err != nil {
+				return err
+			}
+		}
+
+		loan.ID = row.ID
+		loan.CreatedAt = row.CreatedAt
+		return nil
+	})
+}
+
+// GetLoan returns the loan row, or storage.ErrNotFound.
+func (s *Store) GetLoan(ctx context.Context, loanID string) (*storage.Loan, error) {
+	var row db.Loan
+	s.conn(ctx).First(&row, loanID)
+	if row.ID == 0 {
+		return nil, storage.ErrNotFound
+	}
+	return toStorageLoan(row), nil
+}
+
+// ListActiveLoans returns every loan with a positive remaining balance.
+func (s *Store) ListActiveLoans(ctx context.Context) ([]storage.Loan, error) {
+	var rows []db.Loan
+	if err := s.conn(ctx).Where("remaining_balance > 0").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	loans := make([]storage.Loan, 0, len(rows))
+	for _, row := range rows {
+		loans = append(loans, *toStorageLoan(row))
+	}
+	return loans, nil
+}
+
+// LockLoanForUpdate returns the loan row locked with SELECT ... FOR UPDATE.
+func (s *Store) LockLoanForUpdate(ctx context.Context, loanID string) (*storage.Loan, error) {
+	var row db.Loan
+	if err := s.conn(ctx).Raw("SELECT * FROM loans WHERE id = ? FOR UPDATE", loanID).Scan(&row).Error; err != nil {
+		return nil, err
+	}
+	if row.ID == 0 {
+		return nil, storage.ErrNotFound
+	}
+	return toStorageLoan(row), nil
+}
+
+// NextUnpaidRepayment returns the earliest unpaid repayment for loanID.
+func (s *Store) NextUnpaidRepayment(ctx context.Context, loanID uint) (*storage.Repayment, error) {
+	var row db.Repayment
+	if err := s.conn(ctx).Where("loan_id = ? AND paid = ?", loanID, false).
+		Order("week_no asc").First(&row).Error; err != nil {
+		return nil, storage.ErrNoPendingRepayments
+	}
+	return toStorageRepayment(row), nil
+}
+
+// GetRepayment returns the repayment at (loanID, weekNo), or
+// storage.ErrNotFound.
+func (s *Store) GetRepayment(ctx context.Context, loanID uint, weekNo int) (*storage.Repayment, error) {
+	var row db.Repayment
+	if err := s.conn(ctx).Where("loan_id = ? AND week_no = ?", loanID, weekNo).First(&row).Error; err != nil {
+		return nil, storage.ErrNotFound
+	}
+	return toStorageRepayment(row), nil
+}
+
+// UnpaidRepayments returns loanID's unpaid repayments ordered by week_no
+// ascending.
+func (s *Store) UnpaidRepayments(ctx context.Context, loanID uint) ([]storage.Repayment, error) {
+	var rows []db.Repayment
+	if err := s.conn(ctx).Where("loan_id = ? AND paid = ?", loanID, false).
+		Order("week_no asc").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	repayments := make([]storage.Repayment, 0, len(rows))
+	for _, row := range rows {
+		repayments = append(repayments, *toStorageRepayment(row))
+	}
+	return repayments, nil
+}
+
+// ListRepayments returns every repayment for loanID, paid or not, ordered
+// by week_no ascending.
+func (s *Store) ListRepayments(ctx context.Context, loanID uint) ([]storage.Repayment, error) {
+	var rows []db.Repayment
+	if err := s.conn(ctx).Where("loan_id = ?", loanID).
+		Order("week_no asc").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	repayments := make([]storage.Repayment, 0, len(rows))
+	for _, row := range rows {
+		repayments = append(repayments, *toStorageRepayment(row))
+	}
+	return repayments, nil
+}
+
+// MarkRepaymentPaid marks the repayment at (loanID, weekNo) paid.
+func (s *Store) MarkRepaymentPaid(ctx context.Context, loanID uint, weekNo int) error {
+	return s.conn(ctx).Model(&db.Repayment{}).
+		Where("loan_id = ? AND week_no = ?", loanID, weekNo).
+		Updates(map[string]interface{}{"paid": true, "paid_at": time.Now()}).Error
+}
+
+// CountUnpaidBefore counts loanID's unpaid repayments due before week.
+func (s *Store) CountUnpaidBefore(ctx context.Context, loanID uint, week int) (int64, error) {
+	var count int64
+	err := s.conn(ctx).Model(&db.Repayment{}).
+		Where("loan_id = ? AND paid = ? AND week_no < ?", loanID, false, week).
+		Count(&count).Error
+	return count, err
+}
+
+// UpdateBalance sets loanID's remaining balance.
+func (s *Store) UpdateBalance(ctx context.Context, loanID uint, remainingBalance float64) error {
+	return s.conn(ctx).Model(&db.Loan{}).Where("id = ?", loanID).
+		Update("remaining_balance", remainingBalance).Error
+}
+
+func toStorageLoan(row db.Loan) *storage.Loan {
+	return &storage.Loan{
+		ID:               row.ID,
+		BorrowerID:       row.BorrowerID,
+		ProductID:        row.ProductID,
+		Amount:           row.Amount,
+		InterestRate:     row.InterestRate,
+		WeeklyPayment:    row.WeeklyPayment,
+		RemainingBalance: row.RemainingBalance,
+		CreatedAt:        row.CreatedAt,
+	}
+}
+
+func toStorageRepayment(row db.Repayment) *storage.Repayment {
+	return &storage.Repayment{
+		LoanID:       row.LoanID,
+		WeekNo:       row.WeekNo,
+		PrincipalDue: row.PrincipalDue,
+		InterestDue:  row.InterestDue,
+		DueDate:      row.DueDate,
+		Paid:         row.Paid,
+		PaidAt:       row.PaidAt,
+		CreatedAt:    row.CreatedAt,
+	}
+}
@@ -0,0 +1,106 @@
+// Package storage defines the backend-agnostic loan ledger interface. The
+// service package talks to a LoanRepository instead of a specific database,
+// so the ledger can be backed by Postgres (storage/postgres) or Cassandra
+// (storage/cassandra) behind the same API.
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a loan, or a repayment looked up by
+// GetRepayment, doesn't exist.
+var ErrNotFound = errors.New("loan not found")
+
+// ErrNoPendingRepayments is returned when a loan has no unpaid repayment
+// left to apply a payment to.
+var ErrNoPendingRepayments = errors.New("no pending repayments")
+
+// Loan is the backend-agnostic view of a loan.
+type Loan struct {
+	ID               uint      `json:"id"`
+	BorrowerID       uint      `json:"borrower_id"`
+	ProductID        uint      `json:"product_id"`
+	Amount           float64   `json:"amount"`
+	InterestRate     float64   `json:"interest_rate"`
+	WeeklyPayment    float64   `json:"weekly_payment"`
+	RemainingBalance float64   `json:"remaining_balance"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Repayment is the backend-agnostic view of one scheduled installment.
+type Repayment struct {
+	LoanID       uint       `json:"loan_id"`
+	WeekNo       int        `json:"week_no"`
+	PrincipalDue float64    `json:"principal_due"`
+	InterestDue  float64    `json:"interest_due"`
+	DueDate      time.Time  `json:"due_date"`
+	Paid         bool       `json:"paid"`
+	PaidAt       *time.Time `json:"paid_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// RepaymentSchedule is one precomputed installment the service hands to
+// CreateLoan; backends persist the amounts as given rather than
+// recomputing them, since the split between principal and interest
+// depends on the loan product's schedule type.
+type RepaymentSchedule struct {
+	WeekNo       int
+	PrincipalDue float64
+	InterestDue  float64
+	DueDate      time.Time
+}
+
+// LoanRepository is the storage contract the service and worker packages
+// depend on, implemented once per supported backend.
+type LoanRepository interface {
+	// WithinTransaction runs fn with a context that scopes repository calls
+	// to a single atomic unit of work where the backend supports one.
+	// Backends that can't take cross-row locks (e.g. Cassandra) run fn
+	// unmodified and rely on per-row compare-and-set instead.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// CreateLoan persists loan and its precomputed repayment schedule.
+	CreateLoan(ctx context.Context, loan *Loan, schedule []RepaymentSchedule) error
+
+	// GetLoan returns the loan row, or ErrNotFound.
+	GetLoan(ctx context.Context, loanID string) (*Loan, error)
+
+	// ListActiveLoans returns every loan with a positive remaining balance,
+	// for the delinquency sweeper to walk.
+	ListActiveLoans(ctx context.Context) ([]Loan, error)
+
+	// LockLoanForUpdate returns the loan row with whatever exclusivity
+	// guarantee the backend can offer before it is modified.
+	LockLoanForUpdate(ctx context.Context, loanID string) (*Loan, error)
+
+	// NextUnpaidRepayment returns the earliest unpaid repayment for loanID,
+	// or ErrNoPendingRepayments.
+	NextUnpaidRepayment(ctx context.Context, loanID uint) (*Repayment, error)
+
+	// GetRepayment returns the repayment at (loanID, weekNo), or
+	// ErrNotFound. Used to re-derive which installment a retried
+	// payment:apply task already targeted, instead of asking for "next
+	// unpaid" again once that installment is marked paid.
+	GetRepayment(ctx context.Context, loanID uint, weekNo int) (*Repayment, error)
+
+	// UnpaidRepayments returns loanID's unpaid repayments ordered by
+	// week_no ascending, for the delinquency sweeper to walk in schedule
+	// order.
+	UnpaidRepayments(ctx context.Context, loanID uint) ([]Repayment, error)
+
+	// ListRepayments returns every repayment for loanID, paid or not,
+	// ordered by week_no ascending, for the schedule export endpoints.
+	ListRepayments(ctx context.Context, loanID uint) ([]Repayment, error)
+
+	// MarkRepaymentPaid marks the repayment at (loanID, weekNo) paid.
+	MarkRepaymentPaid(ctx context.Context, loanID uint, weekNo int) error
+
+	// CountUnpaidBefore counts loanID's unpaid repayments due before week.
+	CountUnpaidBefore(ctx context.Context, loanID uint, week int) (int64, error)
+
+	// UpdateBalance sets loanID's remaining balance.
+	UpdateBalance(ctx context.Context, loanID uint, remainingBalance float64) error
+}
@@ -0,0 +1,90 @@
+// Package webhook fans out bus events to registered subscriber URLs,
+// HMAC-signing each delivery so subscribers can verify it came from us.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/grenarafa/billing-engine/bus"
+	"github.com/grenarafa/billing-engine/db"
+)
+
+// signatureHeader carries the hex-encoded HMAC-SHA256 of the request body,
+// keyed by the subscription's secret.
+const signatureHeader = "X-Billing-Engine-Signature"
+
+// Dispatcher delivers every bus.Bus message to the webhook subscriptions
+// stored in taskDB.
+type Dispatcher struct {
+	taskDB *gorm.DB
+	client *http.Client
+}
+
+// NewDispatcher builds a Dispatcher backed by taskDB.
+func NewDispatcher(taskDB *gorm.DB) *Dispatcher {
+	return &Dispatcher{taskDB: taskDB, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Run subscribes to b's global channel and delivers every message to all
+// registered subscriptions until ctx is canceled.
+func (d *Dispatcher) Run(ctx context.Context, b *bus.Bus) {
+	sub := b.Subscribe(ctx, bus.GlobalChannel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			d.deliver(ctx, []byte(msg.Payload))
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, payload []byte) {
+	var subs []db.WebhookSubscription
+	if err := d.taskDB.WithContext(ctx).Find(&subs).Error; err != nil {
+		log.Println("webhook: failed to load subscriptions: ", err)
+		return
+	}
+
+	for _, s := range subs {
+		go d.send(ctx, s, payload)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, s db.WebhookSubscription, payload []byte) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		log.Printf("webhook: build request for %s: %v", s.URL, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signatureHeader, sign(s.Secret, payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		log.Printf("webhook: deliver to %s: %v", s.URL, err)
+		return
+	}
+	resp.Body.Close()
+}
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,131 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+
+	"github.com/grenarafa/billing-engine/bus"
+	"github.com/grenarafa/billing-engine/cache"
+	"github.com/grenarafa/billing-engine/db"
+	"github.com/grenarafa/billing-engine/storage"
+)
+
+// Processor applies queued payment:apply tasks against the loan repository
+// and keeps the Redis caches in sync. The payment task bookkeeping row
+// always lives in Postgres, regardless of which LoanRepository backs the
+// loan ledger.
+type Processor struct {
+	repo   storage.LoanRepository
+	taskDB *gorm.DB
+	cache  *redis.Client
+	bus    *bus.Bus
+}
+
+// NewProcessor builds a Processor backed by repo, taskDB, redisClient, and
+// eventBus.
+func NewProcessor(repo storage.LoanRepository, taskDB *gorm.DB, redisClient *redis.Client, eventBus *bus.Bus) *Processor {
+	return &Processor{repo: repo, taskDB: taskDB, cache: redisClient, bus: eventBus}
+}
+
+// ProcessPaymentApply is the asynq.HandlerFunc for TypePaymentApply. It is
+// safe to redeliver: a task whose PaymentTask row is already completed is a
+// no-op.
+func (p *Processor) ProcessPaymentApply(ctx context.Context, task *asynq.Task) error {
+	var payload PaymentApplyPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("unmarshal payment:apply payload: %w", err)
+	}
+
+	taskID, ok := asynq.GetTaskID(ctx)
+	if !ok {
+		return fmt.Errorf("payment:apply task missing task id")
+	}
+
+	var record db.PaymentTask
+	if err := p.taskDB.First(&record, "id = ?", taskID).Error; err != nil {
+		return fmt.Errorf("load payment task %s: %w", taskID, err)
+	}
+	if record.Status == db.PaymentStatusCompleted {
+		return nil
+	}
+
+	p.taskDB.Model(&record).Update("status", db.PaymentStatusProcessing)
+
+	var newBalance float64
+	applyErr := p.repo.WithinTransaction(ctx, func(ctx context.Context) error {
+		loan, err := p.repo.LockLoanForUpdate(ctx, payload.LoanID)
+		if err != nil {
+			return err
+		}
+
+		repayment, err := p.targetRepayment(ctx, &record, loan.ID)
+		if err != nil {
+			return err
+		}
+
+		if !repayment.Paid {
+			if err := p.repo.MarkRepaymentPaid(ctx, loan.ID, repayment.WeekNo); err != nil {
+				return err
+			}
+		}
+
+		newBalance = loan.RemainingBalance - payload.Amount
+		return p.repo.UpdateBalance(ctx, loan.ID, newBalance)
+	})
+
+	if applyErr != nil {
+		// A missing loan or an already fully paid schedule will never
+		// succeed on retry, so fail the task permanently instead of
+		// letting asynq exhaust its retries.
+		if errors.Is(applyErr, storage.ErrNotFound) || errors.Is(applyErr, storage.ErrNoPendingRepayments) {
+			p.taskDB.Model(&record).Updates(map[string]interface{}{
+				"status": db.PaymentStatusFailed,
+				"error":  applyErr.Error(),
+			})
+			return nil
+		}
+		return applyErr
+	}
+
+	p.cache.Set(ctx, cache.OutstandingKey(payload.LoanID), fmt.Sprintf("%f", newBalance), 10*time.Minute)
+	p.cache.Del(ctx, cache.DelinquentKey(payload.LoanID))
+
+	p.taskDB.Model(&record).Update("status", db.PaymentStatusCompleted)
+
+	p.bus.Publish(ctx, payload.LoanID, bus.PaymentReceived, payload)
+	p.bus.Publish(ctx, payload.LoanID, bus.BalanceUpdated, map[string]float64{"remaining_balance": newBalance})
+	if newBalance <= 0 {
+		p.bus.Publish(ctx, payload.LoanID, bus.LoanPaidOff, nil)
+	}
+	return nil
+}
+
+// targetRepayment returns the repayment this task applies to. On a
+// backend without cross-row transactions (Cassandra), MarkRepaymentPaid
+// can succeed and then UpdateBalance fail, so a redelivered task must not
+// simply ask for the next unpaid repayment again — that installment is
+// already paid, and "next unpaid" would now name a different one. Once
+// record.TargetWeekNo is set, every retry re-fetches that same repayment
+// instead.
+func (p *Processor) targetRepayment(ctx context.Context, record *db.PaymentTask, loanID uint) (*storage.Repayment, error) {
+	if record.TargetWeekNo != nil {
+		return p.repo.GetRepayment(ctx, loanID, *record.TargetWeekNo)
+	}
+
+	repayment, err := p.repo.NextUnpaidRepayment(ctx, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.taskDB.Model(record).Update("target_week_no", repayment.WeekNo).Error; err != nil {
+		return nil, err
+	}
+	record.TargetWeekNo = &repayment.WeekNo
+	return repayment, nil
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"math"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// MaxRetry is the number of times asynq will redeliver a payment:apply task
+// before archiving it to the dead letter queue.
+const MaxRetry = 8
+
+// NewServer builds the asynq server that consumes the payments queue with
+// exponential backoff between retries.
+func NewServer(redisAddr string) *asynq.Server {
+	return asynq.NewServer(
+		asynq.RedisClientOpt{Addr: redisAddr},
+		asynq.Config{
+			Queues:         map[string]int{QueuePayments: 1},
+			RetryDelayFunc: retryBackoff,
+		},
+	)
+}
+
+// NewMux registers the payment:apply handler on a fresh ServeMux.
+func NewMux(processor *Processor) *asynq.ServeMux {
+	mux := asynq.NewServeMux()
+	mux.HandleFunc(TypePaymentApply, processor.ProcessPaymentApply)
+	return mux
+}
+
+// retryBackoff doubles the delay with each retry, starting at 10s and
+// capping at 30 minutes.
+func retryBackoff(n int, err error, task *asynq.Task) time.Duration {
+	const base = 10 * time.Second
+	const max = 30 * time.Minute
+
+	delay := base * time.Duration(math.Pow(2, float64(n)))
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
@@ -0,0 +1,33 @@
+// Package worker defines the asynq task types used to apply payments in
+// the background and the server/processor that consume them.
+package worker
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// TypePaymentApply is the asynq task type for applying a single payment.
+const TypePaymentApply = "payment:apply"
+
+// QueuePayments is the asynq queue payment tasks are enqueued on.
+const QueuePayments = "payments"
+
+// PaymentApplyPayload is the body of a payment:apply task.
+type PaymentApplyPayload struct {
+	LoanID         string    `json:"loan_id"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	Amount         float64   `json:"amount"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// NewPaymentApplyTask builds the asynq task for payload.
+func NewPaymentApplyTask(payload PaymentApplyPayload) (*asynq.Task, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TypePaymentApply, b), nil
+}